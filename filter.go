@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter narrows and orders a Store.List call. It is shared by every Store
+// implementation so the in-memory and persistent backends apply identical
+// predicate, sort, and pagination semantics.
+type Filter struct {
+	Status       string
+	Priority     string
+	Components   []string
+	CreatedSince *time.Time
+	CreatedUntil *time.Time
+	Query        string
+	Sort         string
+	Limit        int
+	Offset       int
+}
+
+// ParseFilter builds a Filter from URL query parameters, following
+// Prometheus's /api/v1 conventions: repeatable params accumulate, time
+// bounds accept either RFC3339 timestamps or durations like "30m", "2h",
+// "7d" (interpreted relative to now), and sort/limit/offset page the result.
+func ParseFilter(values url.Values) (Filter, error) {
+	f := Filter{
+		Status:     values.Get("status"),
+		Priority:   values.Get("priority"),
+		Components: values["component"],
+		Query:      values.Get("q"),
+		Sort:       values.Get("sort"),
+	}
+
+	if v := values.Get("created_since"); v != "" {
+		t, err := parseTimeParam(v)
+		if err != nil {
+			return Filter{}, fmt.Errorf("created_since: %w", err)
+		}
+		f.CreatedSince = &t
+	}
+	if v := values.Get("created_until"); v != "" {
+		t, err := parseTimeParam(v)
+		if err != nil {
+			return Filter{}, fmt.Errorf("created_until: %w", err)
+		}
+		f.CreatedUntil = &t
+	}
+
+	switch f.Sort {
+	case "", "created", "-created", "priority":
+	default:
+		return Filter{}, fmt.Errorf("sort: invalid value %q", f.Sort)
+	}
+
+	if v := values.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return Filter{}, fmt.Errorf("limit: invalid value %q", v)
+		}
+		f.Limit = n
+	}
+	if v := values.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return Filter{}, fmt.Errorf("offset: invalid value %q", v)
+		}
+		f.Offset = n
+	}
+
+	return f, nil
+}
+
+// parseTimeParam accepts an RFC3339 timestamp or a duration (e.g. "30m",
+// "2h", "7d"), the latter interpreted as that far before now — matching how
+// Prometheus's range endpoints normalize a time-window input.
+func parseTimeParam(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	d, err := parseDuration(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("not an RFC3339 timestamp or duration: %q", v)
+	}
+	return time.Now().Add(-d), nil
+}
+
+// parseDuration parses a single-unit duration suffixed with s, m, h, d, or
+// w (seconds, minutes, hours, days, weeks). Go's time.ParseDuration doesn't
+// support d/w, which callers commonly use for relative time windows.
+func parseDuration(v string) (time.Duration, error) {
+	if v == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	unit := v[len(v)-1]
+	var perUnit time.Duration
+	switch unit {
+	case 's':
+		perUnit = time.Second
+	case 'm':
+		perUnit = time.Minute
+	case 'h':
+		perUnit = time.Hour
+	case 'd':
+		perUnit = 24 * time.Hour
+	case 'w':
+		perUnit = 7 * 24 * time.Hour
+	default:
+		return 0, fmt.Errorf("unrecognized duration unit in %q", v)
+	}
+	n, err := strconv.Atoi(v[:len(v)-1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", v)
+	}
+	return time.Duration(n) * perUnit, nil
+}
+
+// ApplyFilter applies f's predicates, sort order, and pagination to
+// requests, returning the page of matching items alongside the total
+// number of matches (before pagination). Store implementations fetch their
+// full dataset and delegate here so every backend filters identically.
+func ApplyFilter(requests []FeatureRequest, f Filter) ([]FeatureRequest, int) {
+	matched := make([]FeatureRequest, 0, len(requests))
+	for _, fr := range requests {
+		if matchesFilter(fr, f) {
+			matched = append(matched, fr)
+		}
+	}
+
+	sortRequests(matched, f.Sort)
+
+	total := len(matched)
+	start := f.Offset
+	if start > total {
+		start = total
+	}
+	end := total
+	if f.Limit > 0 && start+f.Limit < end {
+		end = start + f.Limit
+	}
+	return matched[start:end], total
+}
+
+func matchesFilter(fr FeatureRequest, f Filter) bool {
+	if f.Status != "" && fr.Status != f.Status {
+		return false
+	}
+	if f.Priority != "" && fr.Priority != f.Priority {
+		return false
+	}
+	for _, want := range f.Components {
+		if !containsString(fr.AffectedComponents, want) {
+			return false
+		}
+	}
+	if f.CreatedSince != nil && fr.CreatedAt.Before(*f.CreatedSince) {
+		return false
+	}
+	if f.CreatedUntil != nil && fr.CreatedAt.After(*f.CreatedUntil) {
+		return false
+	}
+	if f.Query != "" {
+		q := strings.ToLower(f.Query)
+		if !strings.Contains(strings.ToLower(fr.Title), q) && !strings.Contains(strings.ToLower(fr.Description), q) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(haystack []string, want string) bool {
+	for _, s := range haystack {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
+
+func sortRequests(requests []FeatureRequest, sortBy string) {
+	switch sortBy {
+	case "priority":
+		sort.SliceStable(requests, func(i, j int) bool {
+			return priorityRank(requests[i].Priority) < priorityRank(requests[j].Priority)
+		})
+	case "-created":
+		sort.SliceStable(requests, func(i, j int) bool {
+			return requests[i].CreatedAt.After(requests[j].CreatedAt)
+		})
+	case "created", "":
+		sort.SliceStable(requests, func(i, j int) bool {
+			return requests[i].CreatedAt.Before(requests[j].CreatedAt)
+		})
+	}
+}
+
+func priorityRank(priority string) int {
+	switch priority {
+	case "high":
+		return 0
+	case "medium":
+		return 1
+	case "low":
+		return 2
+	default:
+		return 3
+	}
+}
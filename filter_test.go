@@ -0,0 +1,125 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestMatchesFilter(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	weekAgo := now.Add(-7 * 24 * time.Hour)
+
+	fr := FeatureRequest{
+		Title:              "Add dark mode",
+		Description:        "Let users toggle a dark theme",
+		Priority:           "high",
+		Status:             "accepted",
+		AffectedComponents: []string{"frontend", "settings"},
+		CreatedAt:          now,
+	}
+	future := now.Add(time.Hour)
+
+	cases := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"no predicates matches", Filter{}, true},
+		{"status match", Filter{Status: "accepted"}, true},
+		{"status mismatch", Filter{Status: "rejected"}, false},
+		{"priority match", Filter{Priority: "high"}, true},
+		{"priority mismatch", Filter{Priority: "low"}, false},
+		{"component match", Filter{Components: []string{"frontend"}}, true},
+		{"component all must match", Filter{Components: []string{"frontend", "backend"}}, false},
+		{"component mismatch", Filter{Components: []string{"backend"}}, false},
+		{"query matches title", Filter{Query: "dark mode"}, true},
+		{"query matches description case-insensitively", Filter{Query: "TOGGLE"}, true},
+		{"query mismatch", Filter{Query: "nonexistent"}, false},
+		{"created after since", Filter{CreatedSince: &weekAgo}, true},
+		{"created before since", Filter{CreatedSince: &future}, false},
+		{"created before until", Filter{CreatedUntil: &now}, true},
+		{"created after until", Filter{CreatedUntil: &weekAgo}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := matchesFilter(fr, tc.filter); got != tc.want {
+				t.Errorf("matchesFilter(%+v) = %v, want %v", tc.filter, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyFilterSortAndPaginate(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	requests := []FeatureRequest{
+		{ID: 1, Priority: "low", CreatedAt: base},
+		{ID: 2, Priority: "high", CreatedAt: base.Add(2 * time.Hour)},
+		{ID: 3, Priority: "medium", CreatedAt: base.Add(time.Hour)},
+	}
+
+	items, total := ApplyFilter(requests, Filter{Sort: "-created"})
+	if total != 3 {
+		t.Fatalf("total = %d, want 3", total)
+	}
+	if ids := idsOf(items); ids[0] != 2 || ids[1] != 3 || ids[2] != 1 {
+		t.Errorf("sort -created: got %v", ids)
+	}
+
+	items, total = ApplyFilter(requests, Filter{Sort: "priority"})
+	if ids := idsOf(items); ids[0] != 2 || ids[1] != 3 || ids[2] != 1 {
+		t.Errorf("sort priority: got %v", ids)
+	}
+	_ = total
+
+	items, total = ApplyFilter(requests, Filter{Sort: "created", Limit: 1, Offset: 1})
+	if total != 3 {
+		t.Fatalf("total = %d, want 3", total)
+	}
+	if ids := idsOf(items); len(ids) != 1 || ids[0] != 3 {
+		t.Errorf("paginated page: got %v", ids)
+	}
+}
+
+func idsOf(requests []FeatureRequest) []int {
+	ids := make([]int, len(requests))
+	for i, fr := range requests {
+		ids[i] = fr.ID
+	}
+	return ids
+}
+
+func TestParseTimeParam(t *testing.T) {
+	if _, err := parseTimeParam("2026-01-15T12:00:00Z"); err != nil {
+		t.Errorf("RFC3339 timestamp: unexpected error: %v", err)
+	}
+	if _, err := parseTimeParam("7d"); err != nil {
+		t.Errorf("relative duration: unexpected error: %v", err)
+	}
+	if _, err := parseTimeParam("not-a-time"); err == nil {
+		t.Error("expected error for unparseable input")
+	}
+}
+
+func TestParseFilterRejectsInvalidSort(t *testing.T) {
+	for _, v := range []string{"created", "-created", "priority", ""} {
+		if _, err := ParseFilter(url.Values{"sort": []string{v}}); err != nil {
+			t.Errorf("ParseFilter(sort=%q): unexpected error: %v", v, err)
+		}
+	}
+	if _, err := ParseFilter(url.Values{"sort": []string{"bogus"}}); err == nil {
+		t.Error("ParseFilter(sort=bogus): expected error")
+	}
+}
+
+func TestParseFilterRepeatableComponents(t *testing.T) {
+	values := url.Values{"component": []string{"frontend", "backend"}}
+	f, err := ParseFilter(values)
+	if err != nil {
+		t.Fatalf("ParseFilter: unexpected error: %v", err)
+	}
+	if len(f.Components) != 2 || f.Components[0] != "frontend" || f.Components[1] != "backend" {
+		t.Errorf("Components = %v", f.Components)
+	}
+}
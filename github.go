@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// apiCallTimeout bounds each individual GitHub REST API call, independent
+// of the request's own deadline, so one slow call can't stall the whole
+// conversion pipeline indefinitely.
+const apiCallTimeout = 15 * time.Second
+
+// GitHubConfig holds the credentials and repository coordinates needed to
+// open a pull request from an accepted FeatureRequest.
+type GitHubConfig struct {
+	Token      string
+	Repo       string // "owner/name"
+	BaseBranch string
+}
+
+// githubConfigFromEnv reads GITHUB_TOKEN, GITHUB_REPO and
+// GITHUB_BASE_BRANCH. ok is false if the pipeline isn't configured, in which
+// case the conversion feature is disabled rather than erroring at startup.
+func githubConfigFromEnv() (GitHubConfig, bool) {
+	token := os.Getenv("GITHUB_TOKEN")
+	repo := os.Getenv("GITHUB_REPO")
+	if token == "" || repo == "" {
+		return GitHubConfig{}, false
+	}
+	base := os.Getenv("GITHUB_BASE_BRANCH")
+	if base == "" {
+		base = "main"
+	}
+	return GitHubConfig{Token: token, Repo: repo, BaseBranch: base}, true
+}
+
+// GitHubClient drives the GitHub REST API calls needed to turn an accepted
+// FeatureRequest into a draft PR: branch, scaffold commit, and PR.
+type GitHubClient struct {
+	cfg        GitHubConfig
+	httpClient *http.Client
+	baseURL    string
+}
+
+func NewGitHubClient(cfg GitHubConfig) *GitHubClient {
+	return &GitHubClient{
+		cfg:        cfg,
+		httpClient: &http.Client{},
+		baseURL:    "https://api.github.com",
+	}
+}
+
+// do issues a single GitHub API call bounded by apiCallTimeout, layered on
+// top of whatever deadline ctx already carries.
+func (c *GitHubClient) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, apiCallTimeout)
+	defer cancel()
+
+	var reqBody *bytes.Buffer
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewBuffer(data)
+	} else {
+		reqBody = bytes.NewBuffer(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("github api %s %s: unexpected status %s", method, path, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// branchName derives a short, URL-safe branch name from a request's ID and
+// title, e.g. "frtopr/12-add-dark-mode".
+func branchName(fr FeatureRequest) string {
+	slug := strings.ToLower(fr.Title)
+	slug = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(slug, "-")
+	slug = strings.Trim(slug, "-")
+	if len(slug) > 40 {
+		slug = slug[:40]
+	}
+	return fmt.Sprintf("frtopr/%d-%s", fr.ID, slug)
+}
+
+// scaffoldPath returns the stub file path for an affected component.
+func scaffoldPath(component string) string {
+	slug := regexp.MustCompile(`[^a-zA-Z0-9_-]+`).ReplaceAllString(component, "-")
+	return fmt.Sprintf("frtopr/%s.todo.md", slug)
+}
+
+func scaffoldContent(component string, fr FeatureRequest) string {
+	return fmt.Sprintf("# TODO: %s\n\nScaffolded for feature request #%d: %s\n\n## Acceptance Criteria\n\n%s\n",
+		component, fr.ID, fr.Title, fr.AcceptanceCriteria)
+}
+
+// prBody renders the draft PR description from the request's fields.
+func prBody(fr FeatureRequest) string {
+	return fmt.Sprintf(
+		"## %s\n\n%s\n\n### Acceptance Criteria\n\n%s\n\n### Example Usage\n\n%s\n\n---\nConverted from [feature request #%d](/api/requests/%d).",
+		fr.Title, fr.Description, fr.AcceptanceCriteria, fr.ExampleUsage, fr.ID, fr.ID)
+}
+
+// ConvertToPR runs the full accepted-FeatureRequest-to-draft-PR pipeline:
+// branch from the configured base, a scaffold commit per affected
+// component, and a draft PR whose body embeds the request's details.
+func (c *GitHubClient) ConvertToPR(ctx context.Context, fr FeatureRequest) (prURL string, prNumber int, err error) {
+	branch := branchName(fr)
+
+	var baseRef struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/git/ref/heads/%s", c.cfg.Repo, c.cfg.BaseBranch), nil, &baseRef); err != nil {
+		return "", 0, fmt.Errorf("resolving base branch: %w", err)
+	}
+
+	createRef := struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	}{Ref: "refs/heads/" + branch, SHA: baseRef.Object.SHA}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/git/refs", c.cfg.Repo), createRef, nil); err != nil {
+		return "", 0, fmt.Errorf("creating branch: %w", err)
+	}
+
+	components := fr.AffectedComponents
+	if len(components) == 0 {
+		components = []string{"general"}
+	}
+	for _, component := range components {
+		putFile := struct {
+			Message string `json:"message"`
+			Content string `json:"content"`
+			Branch  string `json:"branch"`
+		}{
+			Message: fmt.Sprintf("Scaffold %s for feature request #%d", component, fr.ID),
+			Content: base64.StdEncoding.EncodeToString([]byte(scaffoldContent(component, fr))),
+			Branch:  branch,
+		}
+		path := fmt.Sprintf("/repos/%s/contents/%s", c.cfg.Repo, scaffoldPath(component))
+		if err := c.do(ctx, http.MethodPut, path, putFile, nil); err != nil {
+			return "", 0, fmt.Errorf("committing scaffold for %s: %w", component, err)
+		}
+	}
+
+	createPR := struct {
+		Title string `json:"title"`
+		Head  string `json:"head"`
+		Base  string `json:"base"`
+		Body  string `json:"body"`
+		Draft bool   `json:"draft"`
+	}{
+		Title: fr.Title,
+		Head:  branch,
+		Base:  c.cfg.BaseBranch,
+		Body:  prBody(fr),
+		Draft: true,
+	}
+	var pr struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/pulls", c.cfg.Repo), createPR, &pr); err != nil {
+		return "", 0, fmt.Errorf("opening pull request: %w", err)
+	}
+
+	return pr.HTMLURL, pr.Number, nil
+}
+
+// handleConvertToPR handles POST /api/requests/{id}/convert.
+func (s *Server) handleConvertToPR(w http.ResponseWriter, r *http.Request, fr FeatureRequest) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.github == nil {
+		writeJSONError(w, "GitHub conversion is not configured", http.StatusServiceUnavailable)
+		return
+	}
+	if fr.Status != "accepted" {
+		writeJSONError(w, "Feature request must be accepted before conversion", http.StatusConflict)
+		return
+	}
+
+	prURL, prNumber, err := s.github.ConvertToPR(r.Context(), fr)
+	if err != nil {
+		writeJSONError(w, fmt.Sprintf("Error opening pull request: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	fr.PRURL = prURL
+	fr.PRNumber = prNumber
+	if err := s.store.Update(r.Context(), fr); err != nil {
+		writeJSONError(w, "Error saving pull request link", http.StatusInternalServerError)
+		return
+	}
+	if _, err := s.store.AppendTimelineItem(r.Context(), fr.ID, TimelineItem{
+		Kind:      TimelineKindComment,
+		Actor:     "github-bot",
+		CreatedAt: time.Now(),
+		Body:      fmt.Sprintf("Opened draft PR #%d: %s", prNumber, prURL),
+	}); err != nil {
+		writeJSONError(w, "Error recording conversion", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONResponse(w, APIResponse{
+		Success: true,
+		Message: "Pull request opened",
+		Data:    fr,
+	})
+}
+
+// handleHTMXConvert handles the "Convert to Pull Request" button on the
+// detail view and returns the refreshed convert section as a fragment.
+func (s *Server) handleHTMXConvert(w http.ResponseWriter, r *http.Request, requestID int) {
+	if r.Method != http.MethodPost {
+		writeHTMLMessage(w, http.StatusMethodNotAllowed, false, "Method not allowed")
+		return
+	}
+
+	fr, err := s.store.Get(r.Context(), requestID)
+	if err == ErrNotFound {
+		writeHTMLMessage(w, http.StatusNotFound, false, "Feature request not found")
+		return
+	}
+	if err != nil {
+		writeHTMLMessage(w, http.StatusInternalServerError, false, "Error loading feature request")
+		return
+	}
+	if s.github == nil {
+		writeHTMLMessage(w, http.StatusServiceUnavailable, false, "GitHub conversion is not configured")
+		return
+	}
+	if fr.Status != "accepted" {
+		writeHTMLMessage(w, http.StatusConflict, false, "Feature request must be accepted before conversion")
+		return
+	}
+
+	prURL, prNumber, err := s.github.ConvertToPR(r.Context(), fr)
+	if err != nil {
+		writeHTMLMessage(w, http.StatusBadGateway, false, fmt.Sprintf("Error opening pull request: %s", err))
+		return
+	}
+
+	fr.PRURL = prURL
+	fr.PRNumber = prNumber
+	if err := s.store.Update(r.Context(), fr); err != nil {
+		writeHTMLMessage(w, http.StatusInternalServerError, false, "Error saving pull request link")
+		return
+	}
+	s.store.AppendTimelineItem(r.Context(), fr.ID, TimelineItem{
+		Kind:      TimelineKindComment,
+		Actor:     "github-bot",
+		CreatedAt: time.Now(),
+		Body:      fmt.Sprintf("Opened draft PR #%d: %s", prNumber, prURL),
+	})
+
+	w.Header().Set("Content-Type", "text/html")
+	if err := renderTemplate(w, "pr-status", fr); err != nil {
+		log.Printf("rendering pr-status template: %v", err)
+	}
+}
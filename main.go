@@ -1,28 +1,33 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"html/template"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 )
 
 type FeatureRequest struct {
-	ID                   int       `json:"id"`
-	Title                string    `json:"title"`
-	Description          string    `json:"description"`
-	AcceptanceCriteria   string    `json:"acceptance_criteria"`
-	Priority             string    `json:"priority"`
-	TargetTimeline       string    `json:"target_timeline"`
-	AffectedComponents   []string  `json:"affected_components"`
-	ExampleUsage         string    `json:"example_usage"`
-	TechnicalConstraints string    `json:"technical_constraints"`
-	CreatedAt            time.Time `json:"created_at"`
-	Status               string    `json:"status"`
+	ID                   int            `json:"id"`
+	Title                string         `json:"title"`
+	Description          string         `json:"description"`
+	AcceptanceCriteria   string         `json:"acceptance_criteria"`
+	Priority             string         `json:"priority"`
+	TargetTimeline       string         `json:"target_timeline"`
+	AffectedComponents   []string       `json:"affected_components"`
+	ExampleUsage         string         `json:"example_usage"`
+	TechnicalConstraints string         `json:"technical_constraints"`
+	CreatedAt            time.Time      `json:"created_at"`
+	Status               string         `json:"status"`
+	Timeline             []TimelineItem `json:"timeline,omitempty"`
+	PRURL                string         `json:"pr_url,omitempty"`
+	PRNumber             int            `json:"pr_number,omitempty"`
 }
 
 type APIResponse struct {
@@ -31,343 +36,114 @@ type APIResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
-var featureRequests []FeatureRequest
-var nextID = 1
+// Server holds the dependencies shared by all handlers. Routing through a
+// Server (instead of package-level state) lets tests inject a fake Store.
+type Server struct {
+	store  Store
+	github *GitHubClient
+}
+
+func NewServer(store Store) *Server {
+	return &Server{store: store}
+}
+
+// routes builds the Server's handler. Pulling this out of main lets tests
+// stand up a real http.Server against an injected Store.
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", handleHome)
+	mux.HandleFunc("/api/submit", s.handleSubmit)
+	mux.HandleFunc("/api/requests", s.handleRequests)
+	mux.HandleFunc("/api/requests/", s.handleRequestSubresource)
+	mux.HandleFunc("/health", handleHealth)
 
-func main() {
-	http.HandleFunc("/", handleHome)
-	http.HandleFunc("/api/submit", handleSubmit)
-	http.HandleFunc("/api/requests", handleRequests)
-	http.HandleFunc("/health", handleHealth)
-	
 	// New HTMX-specific endpoints
-	http.HandleFunc("/htmx/submit", handleHTMXSubmit)
-	http.HandleFunc("/htmx/requests", handleHTMXRequests)
-	http.HandleFunc("/htmx/form", handleHTMXForm)
+	mux.HandleFunc("/htmx/submit", s.handleHTMXSubmit)
+	mux.HandleFunc("/htmx/requests", s.handleHTMXRequests)
+	mux.HandleFunc("/htmx/requests/", s.handleHTMXRequestDetail)
+	mux.HandleFunc("/htmx/form", handleHTMXForm)
+
+	return mux
+}
+
+// durationEnv reads key as a time.Duration (e.g. "5s", "2m"), falling back
+// to def if key is unset or invalid.
+func durationEnv(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %s: %v", key, v, def, err)
+		return def
+	}
+	return d
+}
+
+func main() {
+	store, err := newStoreFromEnv(os.Getenv("FRTOPR_STORE"))
+	if err != nil {
+		log.Fatalf("configuring store: %v", err)
+	}
+	s := NewServer(store)
+
+	if cfg, ok := githubConfigFromEnv(); ok {
+		s.github = NewGitHubClient(cfg)
+	}
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	log.Printf("Server starting on port %s", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: durationEnv("FRTOPR_READ_HEADER_TIMEOUT", 5*time.Second),
+		ReadTimeout:       durationEnv("FRTOPR_READ_TIMEOUT", 15*time.Second),
+		WriteTimeout:      durationEnv("FRTOPR_WRITE_TIMEOUT", 15*time.Second),
+		IdleTimeout:       durationEnv("FRTOPR_IDLE_TIMEOUT", 60*time.Second),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Printf("Server starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("listen: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	log.Print("shutting down, draining in-flight requests")
+
+	grace := durationEnv("FRTOPR_SHUTDOWN_TIMEOUT", 10*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("graceful shutdown did not complete within %s: %v", grace, err)
+	}
 }
 
 func handleHome(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/html")
-	html := `<!DOCTYPE html>
-<html>
-<head>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>Feature Request System</title>
-    <script src="https://unpkg.com/htmx.org@1.9.10"></script>
-    <style>
-        body {
-            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
-            max-width: 800px;
-            margin: 0 auto;
-            padding: 20px;
-            background-color: #f5f5f5;
-        }
-        .container {
-            background: white;
-            padding: 30px;
-            border-radius: 8px;
-            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
-        }
-        h1 {
-            color: #333;
-            border-bottom: 2px solid #007bff;
-            padding-bottom: 10px;
-        }
-        .form-group {
-            margin-bottom: 20px;
-        }
-        label {
-            display: block;
-            margin-bottom: 5px;
-            font-weight: 600;
-            color: #333;
-        }
-        input, textarea, select {
-            width: 100%;
-            padding: 10px;
-            border: 1px solid #ddd;
-            border-radius: 4px;
-            font-size: 14px;
-            box-sizing: border-box;
-        }
-        textarea {
-            resize: vertical;
-            min-height: 100px;
-        }
-        button {
-            background-color: #007bff;
-            color: white;
-            padding: 12px 24px;
-            border: none;
-            border-radius: 4px;
-            cursor: pointer;
-            font-size: 16px;
-            margin-right: 10px;
-        }
-        button:hover {
-            background-color: #0056b3;
-        }
-        button:disabled {
-            background-color: #6c757d;
-            cursor: not-allowed;
-        }
-        .secondary-btn {
-            background-color: #6c757d;
-        }
-        .success-message {
-            background-color: #d4edda;
-            color: #155724;
-            padding: 15px;
-            border-radius: 4px;
-            margin-bottom: 20px;
-            border: 1px solid #c3e6cb;
-        }
-        .error-message {
-            background-color: #f8d7da;
-            color: #721c24;
-            padding: 15px;
-            border-radius: 4px;
-            margin-bottom: 20px;
-            border: 1px solid #f5c6cb;
-        }
-        .feature-item {
-            background: #f8f9fa;
-            padding: 20px;
-            margin-bottom: 15px;
-            border-radius: 4px;
-            border-left: 4px solid #007bff;
-        }
-        .feature-title {
-            font-size: 18px;
-            font-weight: 600;
-            color: #333;
-            margin-bottom: 10px;
-        }
-        .feature-meta {
-            font-size: 12px;
-            color: #666;
-            margin-bottom: 10px;
-        }
-        .tabs {
-            display: flex;
-            margin-bottom: 20px;
-            border-bottom: 1px solid #ddd;
-        }
-        .tab {
-            padding: 10px 20px;
-            cursor: pointer;
-            border-bottom: 2px solid transparent;
-            color: #666;
-            background: none;
-            border-left: none;
-            border-right: none;
-            border-top: none;
-            border-radius: 0;
-            margin-right: 0;
-        }
-        .tab.active {
-            color: #007bff;
-            border-bottom-color: #007bff;
-        }
-        .htmx-request {
-            opacity: 0.5;
-        }
-        .content-section {
-            display: none;
-        }
-        .content-section.active {
-            display: block;
-        }
-        .loading {
-            text-align: center;
-            padding: 20px;
-            color: #666;
-        }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <h1>Feature Request System</h1>
-        
-        <div class="tabs">
-            <button 
-                class="tab active" 
-                onclick="showTab('form')"
-                id="form-tab"
-            >
-                New Request
-            </button>
-            <button 
-                class="tab" 
-                onclick="showTab('list')"
-                id="list-tab"
-                hx-get="/htmx/requests"
-                hx-target="#requests-content"
-                hx-trigger="click"
-            >
-                All Requests
-            </button>
-        </div>
-
-        <div id="form-content" class="content-section active">
-            <div id="message-container"></div>
-            
-            <form 
-                hx-post="/htmx/submit" 
-                hx-target="#message-container"
-                hx-swap="innerHTML"
-                hx-on::after-request="if(event.detail.successful) { document.getElementById('feature-form').reset(); }"
-                id="feature-form"
-            >
-                <div class="form-group">
-                    <label for="title">Feature Title *</label>
-                    <input 
-                        type="text" 
-                        id="title" 
-                        name="title"
-                        placeholder="Brief descriptive title for the feature"
-                        required
-                    >
-                </div>
-
-                <div class="form-group">
-                    <label for="description">Feature Description *</label>
-                    <textarea 
-                        id="description" 
-                        name="description"
-                        placeholder="Detailed description of the feature requirements and functionality"
-                        required
-                    ></textarea>
-                </div>
-
-                <div class="form-group">
-                    <label for="acceptance_criteria">Acceptance Criteria *</label>
-                    <textarea 
-                        id="acceptance_criteria" 
-                        name="acceptance_criteria"
-                        placeholder="Clear, testable criteria that define when this feature is complete"
-                        required
-                    ></textarea>
-                </div>
-
-                <div class="form-group">
-                    <label for="priority">Priority Level *</label>
-                    <select 
-                        id="priority" 
-                        name="priority"
-                        required
-                    >
-                        <option value="">Select priority</option>
-                        <option value="high">High - Critical/Urgent</option>
-                        <option value="medium">Medium - Important</option>
-                        <option value="low">Low - Nice to have</option>
-                    </select>
-                </div>
-
-                <div class="form-group">
-                    <label for="target_timeline">Target Timeline</label>
-                    <input 
-                        type="text" 
-                        id="target_timeline" 
-                        name="target_timeline"
-                        placeholder="e.g., Next Sprint, Q2 2025, 2 weeks"
-                    >
-                </div>
-
-                <div class="form-group">
-                    <label for="affected_components">Affected Components/Modules</label>
-                    <input 
-                        type="text" 
-                        id="affected_components" 
-                        name="affected_components"
-                        placeholder="api, frontend, database, auth-service"
-                    >
-                </div>
-
-                <div class="form-group">
-                    <label for="example_usage">Example Usage Scenarios</label>
-                    <textarea 
-                        id="example_usage" 
-                        name="example_usage"
-                        placeholder="Provide specific examples of how users would interact with this feature"
-                    ></textarea>
-                </div>
-
-                <div class="form-group">
-                    <label for="technical_constraints">Technical Constraints or Preferences</label>
-                    <textarea 
-                        id="technical_constraints" 
-                        name="technical_constraints"
-                        placeholder="Any technical limitations, preferred technologies, or implementation constraints"
-                    ></textarea>
-                </div>
-
-                <button type="submit">Submit Feature Request</button>
-                <button type="button" onclick="document.getElementById('feature-form').reset();" class="secondary-btn">Reset Form</button>
-            </form>
-        </div>
-
-        <div id="list-content" class="content-section">
-            <div id="requests-content">
-                <div class="loading">Click "All Requests" to load feature requests...</div>
-            </div>
-        </div>
-    </div>
-
-    <script>
-        function showTab(tabName) {
-            // Hide all content sections
-            document.querySelectorAll('.content-section').forEach(section => {
-                section.classList.remove('active');
-            });
-            
-            // Remove active class from all tabs
-            document.querySelectorAll('.tab').forEach(tab => {
-                tab.classList.remove('active');
-            });
-            
-            // Show selected content and activate tab
-            document.getElementById(tabName + '-content').classList.add('active');
-            document.getElementById(tabName + '-tab').classList.add('active');
-        }
-
-        // Auto-hide success messages after 5 seconds
-        document.body.addEventListener('htmx:afterSwap', function(event) {
-            if (event.detail.target.id === 'message-container') {
-                const messageEl = event.detail.target.querySelector('.success-message');
-                if (messageEl) {
-                    setTimeout(() => {
-                        messageEl.remove();
-                    }, 5000);
-                }
-            }
-        });
-    </script>
-</body>
-</html>`
-	fmt.Fprint(w, html)
+	if err := renderHTMLTemplate(w, "home.html.tmpl", nil); err != nil {
+		log.Printf("rendering home template: %v", err)
+	}
 }
 
-func handleHTMXSubmit(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleHTMXSubmit(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		w.WriteHeader(http.StatusMethodNotAllowed)
-		fmt.Fprint(w, `<div class="error-message">Method not allowed</div>`)
+		writeHTMLMessage(w, http.StatusMethodNotAllowed, false, "Method not allowed")
 		return
 	}
 
 	// Parse form data
 	if err := r.ParseForm(); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(w, `<div class="error-message">Error parsing form data</div>`)
+		writeHTMLMessage(w, http.StatusBadRequest, false, "Error parsing form data")
 		return
 	}
 
@@ -383,7 +159,7 @@ func handleHTMXSubmit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	featureRequest := FeatureRequest{
-		ID:                   nextID,
+		ID:                   s.store.NextID(r.Context()),
 		Title:                r.FormValue("title"),
 		Description:          r.FormValue("description"),
 		AcceptanceCriteria:   r.FormValue("acceptance_criteria"),
@@ -399,57 +175,51 @@ func handleHTMXSubmit(w http.ResponseWriter, r *http.Request) {
 	// Validate required fields
 	if featureRequest.Title == "" || featureRequest.Description == "" ||
 		featureRequest.AcceptanceCriteria == "" || featureRequest.Priority == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		fmt.Fprint(w, `<div class="error-message">Please fill in all required fields</div>`)
+		writeHTMLMessage(w, http.StatusBadRequest, false, "Please fill in all required fields")
 		return
 	}
 
 	// Save the feature request
-	featureRequests = append(featureRequests, featureRequest)
-	nextID++
+	if _, err := s.store.Create(r.Context(), featureRequest); err != nil {
+		writeHTMLMessage(w, http.StatusInternalServerError, false, "Error saving feature request")
+		return
+	}
 
 	// Return success message
-	fmt.Fprintf(w, `<div class="success-message">Feature request submitted successfully! ID: %d</div>`, featureRequest.ID)
+	writeHTMLMessage(w, http.StatusOK, true, fmt.Sprintf("Feature request submitted successfully! ID: %d", featureRequest.ID))
 }
 
-func handleHTMXRequests(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleHTMXRequests(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		w.WriteHeader(http.StatusMethodNotAllowed)
-		fmt.Fprint(w, `<div class="error-message">Method not allowed</div>`)
+		writeHTMLMessage(w, http.StatusMethodNotAllowed, false, "Method not allowed")
 		return
 	}
 
-	if len(featureRequests) == 0 {
-		fmt.Fprint(w, `<div class="feature-item"><div>No feature requests found.</div></div>`)
+	filter, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		writeHTMLMessage(w, http.StatusBadRequest, false, err.Error())
 		return
 	}
 
-	// Generate HTML for all feature requests
-	for _, request := range featureRequests {
-		fmt.Fprintf(w, `
-		<div class="feature-item">
-			<div class="feature-title">%s</div>
-			<div class="feature-meta">
-				ID: %d | Priority: %s | Created: %s
-			</div>
-			<div>%s</div>
-		</div>`, 
-			template.HTMLEscapeString(request.Title),
-			request.ID,
-			template.HTMLEscapeString(request.Priority),
-			request.CreatedAt.Format("2006-01-02 15:04:05"),
-			template.HTMLEscapeString(request.Description))
+	requests, _, err := s.store.List(r.Context(), filter)
+	if err != nil {
+		writeHTMLMessage(w, http.StatusInternalServerError, false, "Error loading feature requests")
+		return
+	}
+
+	if err := renderHTMLTemplate(w, "request-list.html.tmpl", requests); err != nil {
+		log.Printf("rendering request-list template: %v", err)
 	}
 }
 
 func handleHTMXForm(w http.ResponseWriter, r *http.Request) {
 	// This endpoint could be used to return just the form HTML
 	// if you want to reload/reset the form dynamically
-	fmt.Fprint(w, `<div>Form reset successfully!</div>`)
+	writeHTMLMessage(w, http.StatusOK, true, "Form reset successfully!")
 }
 
 // Keep the original API endpoints for backward compatibility
-func handleSubmit(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -472,7 +242,7 @@ func handleSubmit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	featureRequest := FeatureRequest{
-		ID:                   nextID,
+		ID:                   s.store.NextID(r.Context()),
 		Title:                getStringValue(form, "title"),
 		Description:          getStringValue(form, "description"),
 		AcceptanceCriteria:   getStringValue(form, "acceptance_criteria"),
@@ -491,8 +261,10 @@ func handleSubmit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	featureRequests = append(featureRequests, featureRequest)
-	nextID++
+	if _, err := s.store.Create(r.Context(), featureRequest); err != nil {
+		writeJSONError(w, "Error saving feature request", http.StatusInternalServerError)
+		return
+	}
 
 	writeJSONResponse(w, APIResponse{
 		Success: true,
@@ -501,16 +273,42 @@ func handleSubmit(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func handleRequests(w http.ResponseWriter, r *http.Request) {
+// requestListData is the JSON shape returned by handleRequests: the current
+// page of items, the total number of matches before pagination, and the
+// offset a client should request next (omitted once the list is exhausted).
+type requestListData struct {
+	Items      []FeatureRequest `json:"items"`
+	Total      int              `json:"total"`
+	NextOffset int              `json:"next_offset,omitempty"`
+}
+
+func (s *Server) handleRequests(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	filter, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	requests, total, err := s.store.List(r.Context(), filter)
+	if err != nil {
+		writeJSONError(w, "Error loading feature requests", http.StatusInternalServerError)
+		return
+	}
+
+	data := requestListData{Items: requests, Total: total}
+	if nextOffset := filter.Offset + len(requests); filter.Limit > 0 && nextOffset < total {
+		data.NextOffset = nextOffset
+	}
+
 	writeJSONResponse(w, APIResponse{
 		Success: true,
 		Message: "Feature requests retrieved successfully",
-		Data:    featureRequests,
+		Data:    data,
 	})
 }
 
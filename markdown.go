@@ -0,0 +1,40 @@
+package main
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	mdLink     = regexp.MustCompile(`\[([^\[\]]+)\]\(([^()\s]+)\)`)
+	mdBold     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	mdItalic   = regexp.MustCompile(`[*_]([^*_]+)[*_]`)
+	mdCode     = regexp.MustCompile("`([^`]+)`")
+	safeScheme = regexp.MustCompile(`(?i)^(https?://|mailto:)`)
+)
+
+// renderMarkdown converts the small comment-formatting subset this app
+// supports (bold, italic, inline code, links) to HTML. It escapes the input
+// first and only reintroduces the tags it generates itself, so arbitrary
+// HTML in a comment body can never reach the page; links whose scheme isn't
+// http(s) or mailto are left as plain escaped text instead of becoming an
+// anchor, to keep "javascript:" and similar URIs inert.
+func renderMarkdown(body string) template.HTML {
+	escaped := template.HTMLEscapeString(body)
+
+	escaped = mdLink.ReplaceAllStringFunc(escaped, func(match string) string {
+		parts := mdLink.FindStringSubmatch(match)
+		text, url := parts[1], parts[2]
+		if !safeScheme.MatchString(url) {
+			return match
+		}
+		return `<a href="` + url + `" rel="noopener noreferrer">` + text + `</a>`
+	})
+	escaped = mdCode.ReplaceAllString(escaped, "<code>$1</code>")
+	escaped = mdBold.ReplaceAllString(escaped, "<strong>$1</strong>")
+	escaped = mdItalic.ReplaceAllString(escaped, "<em>$1</em>")
+	escaped = strings.ReplaceAll(escaped, "\n", "<br>")
+
+	return template.HTML(escaped)
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestRenderMarkdown(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"bold", "**strong**", "<strong>strong</strong>"},
+		{"italic", "*emphasis*", "<em>emphasis</em>"},
+		{"code", "`go test`", "<code>go test</code>"},
+		{"link", "[docs](https://example.com)", `<a href="https://example.com" rel="noopener noreferrer">docs</a>`},
+		{"unsafe link scheme is left as text", "[click me](javascript:alert(1))", "[click me](javascript:alert(1))"},
+		{"html is escaped, not interpreted", "<script>alert(1)</script>", "&lt;script&gt;alert(1)&lt;/script&gt;"},
+		{"newline becomes br", "line one\nline two", "line one<br>line two"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := string(renderMarkdown(tc.body)); got != tc.want {
+				t.Errorf("renderMarkdown(%q) = %q, want %q", tc.body, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// blockingStore is a Store whose List blocks until its context is canceled,
+// reporting the observed error on cancel. The other methods are unused by
+// this test and return zero values.
+type blockingStore struct {
+	cancelObserved chan error
+}
+
+func (b *blockingStore) Create(ctx context.Context, fr FeatureRequest) (FeatureRequest, error) {
+	return fr, nil
+}
+func (b *blockingStore) Get(ctx context.Context, id int) (FeatureRequest, error) {
+	return FeatureRequest{}, ErrNotFound
+}
+func (b *blockingStore) List(ctx context.Context, filter Filter) ([]FeatureRequest, int, error) {
+	<-ctx.Done()
+	b.cancelObserved <- ctx.Err()
+	return nil, 0, ctx.Err()
+}
+func (b *blockingStore) Update(ctx context.Context, fr FeatureRequest) error { return nil }
+func (b *blockingStore) AppendTimelineItem(ctx context.Context, requestID int, item TimelineItem) (TimelineItem, error) {
+	return TimelineItem{}, nil
+}
+func (b *blockingStore) UpdateTimelineItem(ctx context.Context, requestID, itemID int, body, editedBy string) (TimelineItem, error) {
+	return TimelineItem{}, nil
+}
+func (b *blockingStore) NextID(ctx context.Context) int { return 0 }
+
+// TestHandleRequestsObservesClientCancellation fires a request that blocks
+// in the store, cancels the client mid-flight, and asserts the handler's
+// r.Context() propagated the cancellation down to the store call.
+func TestHandleRequestsObservesClientCancellation(t *testing.T) {
+	store := &blockingStore{cancelObserved: make(chan error, 1)}
+	srv := httptest.NewServer(NewServer(store).routes())
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/requests", nil)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+
+	reqDone := make(chan struct{})
+	go func() {
+		defer close(reqDone)
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	// Give the handler time to reach the blocking store call before canceling.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-store.cancelObserved:
+		if err == nil {
+			t.Fatal("store observed cancellation but ctx.Err() was nil")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler never observed client cancellation")
+	}
+
+	<-reqDone
+}
+
+// TestHandleRequestCommentItemEdit posts a comment then PATCHes it, and
+// asserts the response carries the edit history.
+func TestHandleRequestCommentItemEdit(t *testing.T) {
+	store := NewMemoryStore()
+	srv := httptest.NewServer(NewServer(store).routes())
+	defer srv.Close()
+
+	fr, err := store.Create(context.Background(), FeatureRequest{ID: store.NextID(context.Background()), Title: "Add dark mode"})
+	if err != nil {
+		t.Fatalf("seeding feature request: %v", err)
+	}
+
+	postBody, _ := json.Marshal(map[string]string{"actor": "ada", "body": "Looks good"})
+	resp, err := http.Post(fmt.Sprintf("%s/api/requests/%d/comments", srv.URL, fr.ID), "application/json", bytes.NewReader(postBody))
+	if err != nil {
+		t.Fatalf("posting comment: %v", err)
+	}
+	var posted APIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&posted); err != nil {
+		t.Fatalf("decoding posted comment: %v", err)
+	}
+	resp.Body.Close()
+	commentID := int(posted.Data.(map[string]interface{})["id"].(float64))
+
+	patchBody, _ := json.Marshal(map[string]string{"actor": "ada", "body": "Looks good, will open a PR"})
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/api/requests/%d/comments/%d", srv.URL, fr.ID, commentID), bytes.NewReader(patchBody))
+	if err != nil {
+		t.Fatalf("building PATCH request: %v", err)
+	}
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("patching comment: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("PATCH status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var edited APIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&edited); err != nil {
+		t.Fatalf("decoding edited comment: %v", err)
+	}
+	item := edited.Data.(map[string]interface{})
+	if item["body"] != "Looks good, will open a PR" {
+		t.Errorf("body = %v, want updated text", item["body"])
+	}
+	if item["edited"] != true {
+		t.Errorf("edited = %v, want true", item["edited"])
+	}
+	if item["edited_by"] != "ada" {
+		t.Errorf("edited_by = %v, want ada", item["edited_by"])
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by a Store when a FeatureRequest (or one of its
+// timeline items) doesn't exist.
+var ErrNotFound = errors.New("feature request not found")
+
+// Store abstracts persistence for feature requests so the server no longer
+// depends on package-level state. Implementations must be safe for
+// concurrent use. List returns the page of requests matching filter
+// alongside the total number of matches, before pagination. Every method
+// takes the request's context so a client disconnect can cancel the
+// underlying call instead of leaving it to run to completion.
+type Store interface {
+	Create(ctx context.Context, fr FeatureRequest) (FeatureRequest, error)
+	Get(ctx context.Context, id int) (FeatureRequest, error)
+	List(ctx context.Context, filter Filter) ([]FeatureRequest, int, error)
+	Update(ctx context.Context, fr FeatureRequest) error
+	AppendTimelineItem(ctx context.Context, requestID int, item TimelineItem) (TimelineItem, error)
+	// UpdateTimelineItem edits an existing comment's body, recording the
+	// edit's author and time. It returns ErrNotFound if requestID or itemID
+	// doesn't identify an existing comment.
+	UpdateTimelineItem(ctx context.Context, requestID, itemID int, body, editedBy string) (TimelineItem, error)
+	NextID(ctx context.Context) int
+}
+
+// MemoryStore is the default Store: an in-memory slice guarded by a mutex.
+// It preserves the behavior of the original package-level globals, with the
+// caveat that its contents do not survive a restart. Its operations never
+// block on I/O, so it only honors ctx by refusing to start already-canceled
+// work.
+type MemoryStore struct {
+	mu         sync.Mutex
+	requests   []FeatureRequest
+	nextID     int
+	nextItemID int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{nextID: 1, nextItemID: 1}
+}
+
+func (m *MemoryStore) NextID(ctx context.Context) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextID
+	m.nextID++
+	return id
+}
+
+func (m *MemoryStore) Create(ctx context.Context, fr FeatureRequest) (FeatureRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return FeatureRequest{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, fr)
+	return fr, nil
+}
+
+func (m *MemoryStore) Get(ctx context.Context, id int) (FeatureRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return FeatureRequest{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, fr := range m.requests {
+		if fr.ID == id {
+			return fr, nil
+		}
+	}
+	return FeatureRequest{}, ErrNotFound
+}
+
+func (m *MemoryStore) List(ctx context.Context, filter Filter) ([]FeatureRequest, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]FeatureRequest, len(m.requests))
+	copy(out, m.requests)
+	items, total := ApplyFilter(out, filter)
+	return items, total, nil
+}
+
+func (m *MemoryStore) Update(ctx context.Context, fr FeatureRequest) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.requests {
+		if m.requests[i].ID == fr.ID {
+			m.requests[i] = fr
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// newStoreFromEnv builds the Store selected by FRTOPR_STORE, e.g.
+// "sqlite:///var/lib/frtopr/db.sqlite" or "bolt:///var/lib/frtopr/db.bolt".
+// An unset (or empty) value preserves the original in-memory behavior.
+func newStoreFromEnv(value string) (Store, error) {
+	if value == "" {
+		return NewMemoryStore(), nil
+	}
+
+	switch {
+	case strings.HasPrefix(value, "sqlite://"):
+		return NewSQLiteStore(strings.TrimPrefix(value, "sqlite://"))
+	case strings.HasPrefix(value, "bolt://"):
+		return NewBoltStore(strings.TrimPrefix(value, "bolt://"))
+	default:
+		return nil, fmt.Errorf("unrecognized FRTOPR_STORE scheme: %q", value)
+	}
+}
+
+func (m *MemoryStore) AppendTimelineItem(ctx context.Context, requestID int, item TimelineItem) (TimelineItem, error) {
+	if err := ctx.Err(); err != nil {
+		return TimelineItem{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.requests {
+		if m.requests[i].ID == requestID {
+			item.ID = m.nextItemID
+			m.nextItemID++
+			m.requests[i].Timeline = append(m.requests[i].Timeline, item)
+			return item, nil
+		}
+	}
+	return TimelineItem{}, ErrNotFound
+}
+
+func (m *MemoryStore) UpdateTimelineItem(ctx context.Context, requestID, itemID int, body, editedBy string) (TimelineItem, error) {
+	if err := ctx.Err(); err != nil {
+		return TimelineItem{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.requests {
+		if m.requests[i].ID != requestID {
+			continue
+		}
+		for j := range m.requests[i].Timeline {
+			item := &m.requests[i].Timeline[j]
+			if item.ID == itemID && item.Kind == TimelineKindComment {
+				now := time.Now()
+				item.Body = body
+				item.Edited = true
+				item.EditedAt = &now
+				item.EditedBy = editedBy
+				return *item, nil
+			}
+		}
+		return TimelineItem{}, ErrNotFound
+	}
+	return TimelineItem{}, ErrNotFound
+}
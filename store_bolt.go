@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	boltRequestsBucket = []byte("requests")
+	boltCountersBucket = []byte("counters")
+)
+
+// BoltStore persists feature requests to an embedded BoltDB database. It is
+// selected by setting FRTOPR_STORE=bolt:///path/to/db. bbolt transactions
+// don't accept a context, so cancellation is honored on a best-effort basis
+// by refusing to start already-canceled work.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("opening bolt store: %w", err)
+	}
+	s := &BoltStore{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating bolt store: %w", err)
+	}
+	return s, nil
+}
+
+func (s *BoltStore) migrate() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(boltRequestsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(boltCountersBucket)
+		return err
+	})
+}
+
+func boltKey(id int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(id))
+	return key
+}
+
+func (s *BoltStore) nextCounter(name string) (int, error) {
+	var next int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		next, err = nextCounterInTx(tx.Bucket(boltCountersBucket), name)
+		return err
+	})
+	return next, err
+}
+
+// nextCounterInTx increments and returns the named counter using a bucket
+// handle from an already-open transaction, so callers that need the
+// increment to participate in a larger atomic operation (e.g.
+// AppendTimelineItem) aren't forced to open a second transaction.
+func nextCounterInTx(b *bolt.Bucket, name string) (int, error) {
+	raw := b.Get([]byte(name))
+	value := 1
+	if raw != nil {
+		value = int(binary.BigEndian.Uint64(raw))
+	}
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(value+1))
+	return value, b.Put([]byte(name), buf)
+}
+
+func (s *BoltStore) NextID(ctx context.Context) int {
+	if err := ctx.Err(); err != nil {
+		return 0
+	}
+	id, err := s.nextCounter("request_id")
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func (s *BoltStore) Create(ctx context.Context, fr FeatureRequest) (FeatureRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return FeatureRequest{}, err
+	}
+	data, err := json.Marshal(fr)
+	if err != nil {
+		return FeatureRequest{}, err
+	}
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltRequestsBucket).Put(boltKey(fr.ID), data)
+	})
+	return fr, err
+}
+
+func (s *BoltStore) Get(ctx context.Context, id int) (FeatureRequest, error) {
+	if err := ctx.Err(); err != nil {
+		return FeatureRequest{}, err
+	}
+	var fr FeatureRequest
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltRequestsBucket).Get(boltKey(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &fr)
+	})
+	return fr, err
+}
+
+func (s *BoltStore) List(ctx context.Context, filter Filter) ([]FeatureRequest, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	var out []FeatureRequest
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltRequestsBucket).ForEach(func(k, v []byte) error {
+			var fr FeatureRequest
+			if err := json.Unmarshal(v, &fr); err != nil {
+				return err
+			}
+			out = append(out, fr)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	items, total := ApplyFilter(out, filter)
+	return items, total, nil
+}
+
+func (s *BoltStore) Update(ctx context.Context, fr FeatureRequest) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(fr)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltRequestsBucket)
+		if b.Get(boltKey(fr.ID)) == nil {
+			return ErrNotFound
+		}
+		return b.Put(boltKey(fr.ID), data)
+	})
+}
+
+// AppendTimelineItem reads the request, assigns the next item ID, and
+// writes the appended timeline back all within a single bolt.Update
+// transaction, so two concurrent appends to the same request can't read the
+// same base Timeline and have one overwrite the other.
+func (s *BoltStore) AppendTimelineItem(ctx context.Context, requestID int, item TimelineItem) (TimelineItem, error) {
+	if err := ctx.Err(); err != nil {
+		return TimelineItem{}, err
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		requests := tx.Bucket(boltRequestsBucket)
+		data := requests.Get(boltKey(requestID))
+		if data == nil {
+			return ErrNotFound
+		}
+		var fr FeatureRequest
+		if err := json.Unmarshal(data, &fr); err != nil {
+			return err
+		}
+
+		counters := tx.Bucket(boltCountersBucket)
+		itemID, err := nextCounterInTx(counters, "timeline_item_id")
+		if err != nil {
+			return err
+		}
+		item.ID = itemID
+		fr.Timeline = append(fr.Timeline, item)
+
+		updated, err := json.Marshal(fr)
+		if err != nil {
+			return err
+		}
+		return requests.Put(boltKey(requestID), updated)
+	})
+	if err != nil {
+		return TimelineItem{}, err
+	}
+	return item, nil
+}
+
+// UpdateTimelineItem reads the request, edits the matching comment, and
+// writes it back within a single bolt.Update transaction for the same
+// atomicity reasons as AppendTimelineItem.
+func (s *BoltStore) UpdateTimelineItem(ctx context.Context, requestID, itemID int, body, editedBy string) (TimelineItem, error) {
+	if err := ctx.Err(); err != nil {
+		return TimelineItem{}, err
+	}
+	var updated TimelineItem
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		requests := tx.Bucket(boltRequestsBucket)
+		data := requests.Get(boltKey(requestID))
+		if data == nil {
+			return ErrNotFound
+		}
+		var fr FeatureRequest
+		if err := json.Unmarshal(data, &fr); err != nil {
+			return err
+		}
+
+		found := false
+		editedAt := time.Now()
+		for i := range fr.Timeline {
+			if fr.Timeline[i].ID == itemID && fr.Timeline[i].Kind == TimelineKindComment {
+				fr.Timeline[i].Body = body
+				fr.Timeline[i].Edited = true
+				fr.Timeline[i].EditedAt = &editedAt
+				fr.Timeline[i].EditedBy = editedBy
+				updated = fr.Timeline[i]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrNotFound
+		}
+
+		newData, err := json.Marshal(fr)
+		if err != nil {
+			return err
+		}
+		return requests.Put(boltKey(requestID), newData)
+	})
+	if err != nil {
+		return TimelineItem{}, err
+	}
+	return updated, nil
+}
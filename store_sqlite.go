@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStore persists feature requests to an embedded SQLite database. It
+// is selected by setting FRTOPR_STORE=sqlite:///path/to/db.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite store: %w", err)
+	}
+	s := &SQLiteStore{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating sqlite store: %w", err)
+	}
+	return s, nil
+}
+
+func (s *SQLiteStore) migrate() error {
+	_, err := s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS requests (
+		id INTEGER PRIMARY KEY,
+		data TEXT NOT NULL
+	);
+	CREATE TABLE IF NOT EXISTS counters (
+		name TEXT PRIMARY KEY,
+		value INTEGER NOT NULL
+	);
+	INSERT OR IGNORE INTO counters (name, value) VALUES ('request_id', 1);
+	INSERT OR IGNORE INTO counters (name, value) VALUES ('timeline_item_id', 1);
+	`)
+	return err
+}
+
+func (s *SQLiteStore) NextID(ctx context.Context) int {
+	id, err := s.nextCounter(ctx, "request_id")
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func (s *SQLiteStore) nextCounter(ctx context.Context, name string) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	value, err := sqliteNextCounterInTx(ctx, tx, name)
+	if err != nil {
+		return 0, err
+	}
+	return value, tx.Commit()
+}
+
+// sqliteNextCounterInTx increments and returns the named counter using a
+// transaction handle supplied by the caller, so callers that need the
+// increment to participate in a larger atomic operation (e.g.
+// AppendTimelineItem) aren't forced to open a second transaction.
+func sqliteNextCounterInTx(ctx context.Context, tx *sql.Tx, name string) (int, error) {
+	var value int
+	if err := tx.QueryRowContext(ctx, `SELECT value FROM counters WHERE name = ?`, name).Scan(&value); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE counters SET value = ? WHERE name = ?`, value+1, name); err != nil {
+		return 0, err
+	}
+	return value, nil
+}
+
+func (s *SQLiteStore) Create(ctx context.Context, fr FeatureRequest) (FeatureRequest, error) {
+	data, err := json.Marshal(fr)
+	if err != nil {
+		return FeatureRequest{}, err
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT INTO requests (id, data) VALUES (?, ?)`, fr.ID, string(data))
+	return fr, err
+}
+
+func (s *SQLiteStore) Get(ctx context.Context, id int) (FeatureRequest, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM requests WHERE id = ?`, id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return FeatureRequest{}, ErrNotFound
+	}
+	if err != nil {
+		return FeatureRequest{}, err
+	}
+	var fr FeatureRequest
+	if err := json.Unmarshal([]byte(data), &fr); err != nil {
+		return FeatureRequest{}, err
+	}
+	return fr, nil
+}
+
+func (s *SQLiteStore) List(ctx context.Context, filter Filter) ([]FeatureRequest, int, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM requests ORDER BY id`)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var out []FeatureRequest
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, 0, err
+		}
+		var fr FeatureRequest
+		if err := json.Unmarshal([]byte(data), &fr); err != nil {
+			return nil, 0, err
+		}
+		out = append(out, fr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	items, total := ApplyFilter(out, filter)
+	return items, total, nil
+}
+
+func (s *SQLiteStore) Update(ctx context.Context, fr FeatureRequest) error {
+	data, err := json.Marshal(fr)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx, `UPDATE requests SET data = ? WHERE id = ?`, string(data), fr.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AppendTimelineItem reads the request, assigns the next item ID, and
+// writes the appended timeline back all within a single transaction, so two
+// concurrent appends to the same request can't read the same base Timeline
+// and have one overwrite the other.
+func (s *SQLiteStore) AppendTimelineItem(ctx context.Context, requestID int, item TimelineItem) (TimelineItem, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return TimelineItem{}, err
+	}
+	defer tx.Rollback()
+
+	var data string
+	err = tx.QueryRowContext(ctx, `SELECT data FROM requests WHERE id = ?`, requestID).Scan(&data)
+	if err == sql.ErrNoRows {
+		return TimelineItem{}, ErrNotFound
+	}
+	if err != nil {
+		return TimelineItem{}, err
+	}
+	var fr FeatureRequest
+	if err := json.Unmarshal([]byte(data), &fr); err != nil {
+		return TimelineItem{}, err
+	}
+
+	itemID, err := sqliteNextCounterInTx(ctx, tx, "timeline_item_id")
+	if err != nil {
+		return TimelineItem{}, err
+	}
+	item.ID = itemID
+	fr.Timeline = append(fr.Timeline, item)
+
+	updated, err := json.Marshal(fr)
+	if err != nil {
+		return TimelineItem{}, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE requests SET data = ? WHERE id = ?`, string(updated), requestID); err != nil {
+		return TimelineItem{}, err
+	}
+
+	return item, tx.Commit()
+}
+
+// UpdateTimelineItem reads the request, edits the matching comment, and
+// writes it back within a single transaction for the same atomicity reasons
+// as AppendTimelineItem.
+func (s *SQLiteStore) UpdateTimelineItem(ctx context.Context, requestID, itemID int, body, editedBy string) (TimelineItem, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return TimelineItem{}, err
+	}
+	defer tx.Rollback()
+
+	var data string
+	err = tx.QueryRowContext(ctx, `SELECT data FROM requests WHERE id = ?`, requestID).Scan(&data)
+	if err == sql.ErrNoRows {
+		return TimelineItem{}, ErrNotFound
+	}
+	if err != nil {
+		return TimelineItem{}, err
+	}
+	var fr FeatureRequest
+	if err := json.Unmarshal([]byte(data), &fr); err != nil {
+		return TimelineItem{}, err
+	}
+
+	var updated TimelineItem
+	found := false
+	editedAt := time.Now()
+	for i := range fr.Timeline {
+		if fr.Timeline[i].ID == itemID && fr.Timeline[i].Kind == TimelineKindComment {
+			fr.Timeline[i].Body = body
+			fr.Timeline[i].Edited = true
+			fr.Timeline[i].EditedAt = &editedAt
+			fr.Timeline[i].EditedBy = editedBy
+			updated = fr.Timeline[i]
+			found = true
+			break
+		}
+	}
+	if !found {
+		return TimelineItem{}, ErrNotFound
+	}
+
+	newData, err := json.Marshal(fr)
+	if err != nil {
+		return TimelineItem{}, err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE requests SET data = ? WHERE id = ?`, string(newData), requestID); err != nil {
+		return TimelineItem{}, err
+	}
+
+	return updated, tx.Commit()
+}
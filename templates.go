@@ -0,0 +1,140 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//go:embed _data/*.html.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(templateFuncMap()).ParseFS(templateFS, "_data/*.html.tmpl"))
+
+func templateFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"priorityBadge":    priorityBadgeHTML,
+		"changeStateBadge": changeStateBadgeHTML,
+		"reltime":          reltime,
+		"avatar":           avatarHTML,
+		"oobAttr":          oobAttr,
+		"markdown":         renderMarkdown,
+		"derefTime":        derefTime,
+	}
+}
+
+// timelineItemView adds the rendering context (which request this item
+// belongs to, and whether this is an out-of-band append) that the
+// comment/event templates need but TimelineItem itself doesn't carry.
+type timelineItemView struct {
+	TimelineItem
+	RequestID int
+	OOB       bool
+}
+
+func newTimelineItemView(requestID int, item TimelineItem, oob bool) timelineItemView {
+	return timelineItemView{TimelineItem: item, RequestID: requestID, OOB: oob}
+}
+
+// oobAttr renders the hx-swap-oob attribute (including its leading space)
+// when oob is set, so templates can embed it directly after a tag's id
+// attribute without a conditional splitting the tag across branches.
+func oobAttr(requestID int, oob bool) template.HTMLAttr {
+	if !oob {
+		return ""
+	}
+	return template.HTMLAttr(fmt.Sprintf(` hx-swap-oob="beforeend:#timeline-%d"`, requestID))
+}
+
+func priorityBadgeHTML(priority string) template.HTML {
+	label := priority
+	if label == "" {
+		label = "unspecified"
+	}
+	return template.HTML(fmt.Sprintf(`<span class="badge badge-priority-%s">%s</span>`,
+		template.HTMLEscapeString(strings.ToLower(priority)), template.HTMLEscapeString(titleCase(label))))
+}
+
+func changeStateBadgeHTML(from, to string) template.HTML {
+	return template.HTML(fmt.Sprintf(`<span class="badge badge-transition">%s &rarr; %s</span>`,
+		template.HTMLEscapeString(titleCase(from)), template.HTMLEscapeString(titleCase(to))))
+}
+
+func avatarHTML(actor string) template.HTML {
+	initial := "?"
+	if actor != "" {
+		initial = strings.ToUpper(actor[:1])
+	}
+	return template.HTML(fmt.Sprintf(`<span class="avatar" title="%s">%s</span>`,
+		template.HTMLEscapeString(actor), template.HTMLEscapeString(initial)))
+}
+
+// reltime renders a human-friendly relative timestamp, e.g. "5 minutes ago".
+func reltime(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		n := int(d / time.Minute)
+		return fmt.Sprintf("%d minute%s ago", n, plural(n))
+	case d < 24*time.Hour:
+		n := int(d / time.Hour)
+		return fmt.Sprintf("%d hour%s ago", n, plural(n))
+	case d < 30*24*time.Hour:
+		n := int(d / (24 * time.Hour))
+		return fmt.Sprintf("%d day%s ago", n, plural(n))
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func derefTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// renderTemplate executes a named template straight to w.
+func renderTemplate(w io.Writer, name string, data interface{}) error {
+	return templates.ExecuteTemplate(w, name, data)
+}
+
+// renderHTMLTemplate sets the HTML content type on w before rendering.
+func renderHTMLTemplate(w http.ResponseWriter, name string, data interface{}) error {
+	w.Header().Set("Content-Type", "text/html")
+	return renderTemplate(w, name, data)
+}
+
+type messageData struct {
+	Success bool
+	Text    string
+}
+
+// writeHTMLMessage renders the shared success/error message fragment used by
+// every HTMX endpoint, setting the HTTP status first.
+func writeHTMLMessage(w http.ResponseWriter, status int, success bool, text string) {
+	w.WriteHeader(status)
+	if err := renderTemplate(w, "message.html.tmpl", messageData{Success: success, Text: text}); err != nil {
+		fmt.Fprint(w, text)
+	}
+}
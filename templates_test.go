@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+var fixtureTime = time.Date(2020, 1, 2, 3, 4, 0, 0, time.UTC)
+
+func fixtureRequest() FeatureRequest {
+	return FeatureRequest{
+		ID:          42,
+		Title:       "Add <b>dark mode</b>",
+		Description: "Let users toggle a dark theme from settings.",
+		Priority:    "high",
+		Status:      "submitted",
+		CreatedAt:   fixtureTime,
+	}
+}
+
+func fixtureRequestWithPR() FeatureRequest {
+	fr := fixtureRequest()
+	fr.Status = "accepted"
+	fr.PRURL = "https://github.com/example/repo/pull/7"
+	fr.PRNumber = 7
+	return fr
+}
+
+func fixtureComment() timelineItemView {
+	return timelineItemView{
+		TimelineItem: TimelineItem{
+			ID:        1,
+			Kind:      TimelineKindComment,
+			Actor:     "ada",
+			CreatedAt: fixtureTime,
+			Body:      "Looks good, <script>alert(1)</script>",
+		},
+		RequestID: 42,
+	}
+}
+
+func fixtureCommentMarkdown() timelineItemView {
+	return timelineItemView{
+		TimelineItem: TimelineItem{
+			ID:        3,
+			Kind:      TimelineKindComment,
+			Actor:     "grace",
+			CreatedAt: fixtureTime,
+			Body:      "**Looks good** — see [the docs](https://example.com/docs) and `go test ./...`",
+		},
+		RequestID: 42,
+	}
+}
+
+func fixtureCommentEdited() timelineItemView {
+	editedAt := fixtureTime.Add(time.Hour)
+	return timelineItemView{
+		TimelineItem: TimelineItem{
+			ID:        4,
+			Kind:      TimelineKindComment,
+			Actor:     "ada",
+			CreatedAt: fixtureTime,
+			Edited:    true,
+			EditedAt:  &editedAt,
+			EditedBy:  "ada",
+			Body:      "Actually, let's use `prefers-color-scheme` instead",
+		},
+		RequestID: 42,
+	}
+}
+
+func fixtureEvent() timelineItemView {
+	return timelineItemView{
+		TimelineItem: TimelineItem{
+			ID:        2,
+			Kind:      TimelineKindEvent,
+			Actor:     "system",
+			CreatedAt: fixtureTime,
+			Transition: &StateTransition{
+				From: "submitted",
+				To:   "accepted",
+			},
+		},
+		RequestID: 42,
+	}
+}
+
+func TestTemplatesGolden(t *testing.T) {
+	cases := []struct {
+		name string
+		tmpl string
+		data interface{}
+	}{
+		{"home", "home.html.tmpl", nil},
+		{"request-item", "request-item.html.tmpl", fixtureRequest()},
+		{"request-item-accepted", "request-item.html.tmpl", fixtureRequestWithPR()},
+		{"request-list-empty", "request-list.html.tmpl", []FeatureRequest{}},
+		{"request-list", "request-list.html.tmpl", []FeatureRequest{fixtureRequest(), fixtureRequestWithPR()}},
+		{"comment", "comment.html.tmpl", fixtureComment()},
+		{"comment-markdown", "comment.html.tmpl", fixtureCommentMarkdown()},
+		{"comment-edited", "comment.html.tmpl", fixtureCommentEdited()},
+		{"event", "event.html.tmpl", fixtureEvent()},
+		{"message-success", "message.html.tmpl", messageData{Success: true, Text: "Feature request submitted successfully! ID: 42"}},
+		{"message-error", "message.html.tmpl", messageData{Success: false, Text: "Please fill in all required fields"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := renderTemplate(&buf, tc.tmpl, tc.data); err != nil {
+				t.Fatalf("rendering %s: %v", tc.tmpl, err)
+			}
+
+			golden := filepath.Join("_data", "testdata", tc.name+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+					t.Fatalf("writing golden file: %v", err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("reading golden file: %v", err)
+			}
+			if buf.String() != string(want) {
+				t.Errorf("%s mismatch\ngot:\n%s\nwant:\n%s", tc.tmpl, buf.String(), string(want))
+			}
+		})
+	}
+}
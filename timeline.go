@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimelineItemKind discriminates the two kinds of entries that can appear in
+// a FeatureRequest's discussion timeline.
+type TimelineItemKind string
+
+const (
+	TimelineKindComment TimelineItemKind = "comment"
+	TimelineKindEvent   TimelineItemKind = "event"
+)
+
+// StateTransition is the structured payload carried by a "event" timeline
+// item, e.g. {from: "submitted", to: "accepted"}.
+type StateTransition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// TimelineItem is a single entry in a FeatureRequest's discussion timeline.
+// Clients distinguish comments from state events via Kind; comments carry
+// Body (Markdown, rendered via the "markdown" template func) and an optional
+// edit history, events carry Transition.
+type TimelineItem struct {
+	ID         int              `json:"id"`
+	Kind       TimelineItemKind `json:"kind"`
+	Actor      string           `json:"actor"`
+	CreatedAt  time.Time        `json:"created_at"`
+	Edited     bool             `json:"edited,omitempty"`
+	EditedAt   *time.Time       `json:"edited_at,omitempty"`
+	EditedBy   string           `json:"edited_by,omitempty"`
+	Body       string           `json:"body,omitempty"`
+	Transition *StateTransition `json:"transition,omitempty"`
+}
+
+// appendStateEvent records a status transition on a request's timeline. It
+// is the mechanism by which status changes (e.g. the GitHub conversion
+// pipeline moving a request to "accepted") become visible in the discussion.
+func (s *Server) appendStateEvent(ctx context.Context, requestID int, actor, from, to string) (TimelineItem, error) {
+	item := TimelineItem{
+		Kind:      TimelineKindEvent,
+		Actor:     actor,
+		CreatedAt: time.Now(),
+		Transition: &StateTransition{
+			From: from,
+			To:   to,
+		},
+	}
+	return s.store.AppendTimelineItem(ctx, requestID, item)
+}
+
+// handleRequestSubresource dispatches /api/requests/{id}/{comments,events,status}.
+func (s *Server) handleRequestSubresource(w http.ResponseWriter, r *http.Request) {
+	id, sub, ok := parseRequestSubpath(r.URL.Path)
+	if !ok {
+		writeJSONError(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	fr, err := s.store.Get(r.Context(), id)
+	if err == ErrNotFound {
+		writeJSONError(w, "Feature request not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeJSONError(w, "Error loading feature request", http.StatusInternalServerError)
+		return
+	}
+
+	switch {
+	case sub == "comments":
+		s.handleRequestComments(w, r, fr)
+	case strings.HasPrefix(sub, "comments/"):
+		itemID, err := strconv.Atoi(strings.TrimPrefix(sub, "comments/"))
+		if err != nil {
+			writeJSONError(w, "Not found", http.StatusNotFound)
+			return
+		}
+		s.handleRequestCommentItem(w, r, fr, itemID)
+	case sub == "events":
+		s.handleRequestEvents(w, r, fr)
+	case sub == "status":
+		s.handleRequestStatus(w, r, fr)
+	case sub == "convert":
+		s.handleConvertToPR(w, r, fr)
+	default:
+		writeJSONError(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// parseRequestSubpath splits "/api/requests/{id}/{sub}" into its id and sub
+// components. sub may itself contain a further "/" (e.g. "comments/3" for a
+// single comment), so it's returned whole for the caller to dispatch on.
+func parseRequestSubpath(path string) (id int, sub string, ok bool) {
+	trimmed := strings.TrimPrefix(path, "/api/requests/")
+	parts := strings.SplitN(trimmed, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return 0, "", false
+	}
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", false
+	}
+	return id, parts[1], true
+}
+
+func (s *Server) handleRequestComments(w http.ResponseWriter, r *http.Request, fr FeatureRequest) {
+	switch r.Method {
+	case http.MethodPost:
+		var body struct {
+			Actor string `json:"actor"`
+			Body  string `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSONError(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if strings.TrimSpace(body.Body) == "" {
+			writeJSONError(w, "Comment body is required", http.StatusBadRequest)
+			return
+		}
+		actor := body.Actor
+		if actor == "" {
+			actor = "anonymous"
+		}
+
+		item, err := s.store.AppendTimelineItem(r.Context(), fr.ID, TimelineItem{
+			Kind:      TimelineKindComment,
+			Actor:     actor,
+			CreatedAt: time.Now(),
+			Body:      body.Body,
+		})
+		if err != nil {
+			writeJSONError(w, "Error saving comment", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSONResponse(w, APIResponse{
+			Success: true,
+			Message: "Comment added",
+			Data:    item,
+		})
+	case http.MethodGet:
+		writeJSONResponse(w, APIResponse{
+			Success: true,
+			Message: "Comments retrieved successfully",
+			Data:    filterTimeline(fr.Timeline, TimelineKindComment),
+		})
+	default:
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleRequestCommentItem handles PATCH /api/requests/{id}/comments/{itemID},
+// editing a comment's body and recording who edited it and when.
+func (s *Server) handleRequestCommentItem(w http.ResponseWriter, r *http.Request, fr FeatureRequest, itemID int) {
+	if r.Method != http.MethodPatch {
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		Actor string `json:"actor"`
+		Body  string `json:"body"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSONError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(body.Body) == "" {
+		writeJSONError(w, "Comment body is required", http.StatusBadRequest)
+		return
+	}
+	actor := body.Actor
+	if actor == "" {
+		actor = "anonymous"
+	}
+
+	item, err := s.store.UpdateTimelineItem(r.Context(), fr.ID, itemID, body.Body, actor)
+	if err == ErrNotFound {
+		writeJSONError(w, "Comment not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeJSONError(w, "Error updating comment", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONResponse(w, APIResponse{
+		Success: true,
+		Message: "Comment updated",
+		Data:    item,
+	})
+}
+
+func (s *Server) handleRequestEvents(w http.ResponseWriter, r *http.Request, fr FeatureRequest) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSONResponse(w, APIResponse{
+		Success: true,
+		Message: "Events retrieved successfully",
+		Data:    filterTimeline(fr.Timeline, TimelineKindEvent),
+	})
+}
+
+// handleRequestStatus transitions a request's status and records the
+// transition as a timeline event.
+func (s *Server) handleRequestStatus(w http.ResponseWriter, r *http.Request, fr FeatureRequest) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		Status string `json:"status"`
+		Actor  string `json:"actor"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSONError(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if body.Status == "" {
+		writeJSONError(w, "Status is required", http.StatusBadRequest)
+		return
+	}
+	actor := body.Actor
+	if actor == "" {
+		actor = "system"
+	}
+
+	from := fr.Status
+	fr.Status = body.Status
+	if err := s.store.Update(r.Context(), fr); err != nil {
+		writeJSONError(w, "Error updating status", http.StatusInternalServerError)
+		return
+	}
+
+	event, err := s.appendStateEvent(r.Context(), fr.ID, actor, from, body.Status)
+	if err != nil {
+		writeJSONError(w, "Error recording status event", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONResponse(w, APIResponse{
+		Success: true,
+		Message: "Status updated",
+		Data:    event,
+	})
+}
+
+func filterTimeline(items []TimelineItem, kind TimelineItemKind) []TimelineItem {
+	filtered := make([]TimelineItem, 0, len(items))
+	for _, item := range items {
+		if item.Kind == kind {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// handleHTMXRequestDetail renders the request detail view, including the
+// interleaved Discussion tab, for "/htmx/requests/{id}".
+func (s *Server) handleHTMXRequestDetail(w http.ResponseWriter, r *http.Request) {
+	trimmed := strings.TrimPrefix(r.URL.Path, "/htmx/requests/")
+	parts := strings.SplitN(trimmed, "/", 2)
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		writeHTMLMessage(w, http.StatusBadRequest, false, "Invalid request ID")
+		return
+	}
+
+	if len(parts) == 2 && parts[1] == "convert" {
+		s.handleHTMXConvert(w, r, id)
+		return
+	}
+
+	fr, err := s.store.Get(r.Context(), id)
+	if err == ErrNotFound {
+		writeHTMLMessage(w, http.StatusNotFound, false, "Feature request not found")
+		return
+	}
+	if err != nil {
+		writeHTMLMessage(w, http.StatusInternalServerError, false, "Error loading feature request")
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		s.handleHTMXAddComment(w, r, fr.ID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprintf(w, `<div id="request-detail-%d">`, fr.ID)
+	if err := renderTemplate(w, "request-item.html.tmpl", fr); err != nil {
+		log.Printf("rendering request-item template: %v", err)
+	}
+	fmt.Fprintf(w, `<h3>Discussion</h3><div id="timeline-%d">`, fr.ID)
+	for _, item := range fr.Timeline {
+		if err := s.writeTimelineItemFragment(w, fr.ID, item, false); err != nil {
+			log.Printf("rendering timeline item template: %v", err)
+		}
+	}
+	fmt.Fprintf(w, `</div>
+		<form hx-post="/htmx/requests/%d" hx-swap="none" hx-on::after-request="if(event.detail.successful) this.reset()">
+			<input type="text" name="actor" placeholder="Your name">
+			<textarea name="body" placeholder="Add a comment (Markdown supported)" required></textarea>
+			<button type="submit">Comment</button>
+		</form>
+	</div>`, fr.ID)
+}
+
+// handleHTMXAddComment appends a comment and returns it as an out-of-band
+// swap so it's appended to the open timeline without a full page reload.
+func (s *Server) handleHTMXAddComment(w http.ResponseWriter, r *http.Request, requestID int) {
+	if err := r.ParseForm(); err != nil {
+		writeHTMLMessage(w, http.StatusBadRequest, false, "Error parsing form data")
+		return
+	}
+
+	body := strings.TrimSpace(r.FormValue("body"))
+	if body == "" {
+		writeHTMLMessage(w, http.StatusBadRequest, false, "Comment body is required")
+		return
+	}
+	actor := r.FormValue("actor")
+	if actor == "" {
+		actor = "anonymous"
+	}
+
+	item, err := s.store.AppendTimelineItem(r.Context(), requestID, TimelineItem{
+		Kind:      TimelineKindComment,
+		Actor:     actor,
+		CreatedAt: time.Now(),
+		Body:      body,
+	})
+	if err != nil {
+		writeHTMLMessage(w, http.StatusInternalServerError, false, "Error saving comment")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	if err := s.writeTimelineItemFragment(w, requestID, item, true); err != nil {
+		log.Printf("rendering timeline item template: %v", err)
+	}
+}
+
+// writeTimelineItemFragment renders a single comment or event. When oob is
+// true, the fragment carries hx-swap-oob so htmx appends it to the open
+// timeline container out-of-band instead of replacing the response target.
+func (s *Server) writeTimelineItemFragment(w http.ResponseWriter, requestID int, item TimelineItem, oob bool) error {
+	name := "comment.html.tmpl"
+	if item.Kind == TimelineKindEvent {
+		name = "event.html.tmpl"
+	}
+	return renderTemplate(w, name, newTimelineItemView(requestID, item, oob))
+}